@@ -0,0 +1,29 @@
+package policy
+
+import "time"
+
+// Well-known topics published by Handler.Run to its notifier, if one is
+// configured.
+const (
+	TopicPolicyReceived          = "policy.received"
+	TopicPolicyChanged           = "policy.changed"
+	TopicPolicyEvaluationSkipped = "policy.evaluation.skipped"
+	TopicPolicyCooldownEnter     = "policy.cooldown.enter"
+	TopicPolicyCooldownExit      = "policy.cooldown.exit"
+	TopicPolicyTargetGone        = "policy.target.gone"
+)
+
+// Event is the value published to notifier subscribers for policy
+// lifecycle and evaluation topics.
+type Event struct {
+	PolicyID PolicyID
+	Time     time.Time
+
+	// Diff describes the change between the previous and new policy, for
+	// TopicPolicyChanged events.
+	Diff string
+
+	// Cooldown is the duration of the cooldown being entered or exited, for
+	// TopicPolicyCooldownEnter/Exit events.
+	Cooldown time.Duration
+}
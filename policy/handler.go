@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/nomad-autoscaler/plugins"
 	"github.com/hashicorp/nomad-autoscaler/plugins/manager"
 	targetpkg "github.com/hashicorp/nomad-autoscaler/plugins/target"
+	"github.com/hashicorp/nomad-autoscaler/policy/notifier"
 )
 
 // Handler monitors a policy for changes and controls when them are sent for
@@ -31,12 +32,60 @@ type Handler struct {
 	// is responsible for.
 	policySource Source
 
-	// ticker controls the frequency the policy is sent for evaluation.
-	ticker *time.Ticker
-
-	// cooldownCh is used to notify the handler that it should enter a cooldown
-	// period.
-	cooldownCh chan time.Duration
+	// policyReadTimeout is how long the handler waits for the policy source
+	// to deliver the policy before giving up and exiting.
+	policyReadTimeout time.Duration
+
+	// notifier publishes policy lifecycle and evaluation events. It is
+	// optional; a nil notifier means events are simply not published.
+	notifier *notifier.Notifier
+
+	// leaderElector gates evaluation dispatch so that, when multiple
+	// autoscaler instances run against the same cluster, only the leader's
+	// Run loop actually evaluates the policy. Defaults to a no-op elector
+	// that is always the leader.
+	leaderElector LeaderElector
+
+	// electionRetryInterval is how long runElection waits before retrying
+	// after a failed Acquire call. It's a separate setting from
+	// policyReadTimeout - one bounds how long to wait for policy data, the
+	// other how eagerly to retry leadership - even though both happen to
+	// default to a similar order of magnitude.
+	electionRetryInterval time.Duration
+
+	// sched controls when the policy is sent for evaluation. It defaults to
+	// a plain interval but can be swapped for a cron or calendar based
+	// schedule via Policy.Schedule.
+	sched scheduler
+
+	// schedCh is the channel currently armed by sched. It is only
+	// refreshed (via armSchedule) right after it fires or the schedule is
+	// rebuilt, never on every loop iteration, since re-reading sched.Next()
+	// directly in a select case would rearm the timer on every pass through
+	// the loop regardless of which case actually fired. It is nil while the
+	// handler is in standby, since only the leader dispatches evaluations;
+	// a nil channel is never ready in a select, so the schedCh case simply
+	// never fires until armSchedule is called again on becoming leader.
+	schedCh <-chan time.Time
+
+	// isLeader records whether this instance currently holds leadership, so
+	// that updateHandler knows whether to arm the rebuilt schedule.
+	isLeader     bool
+	isLeaderLock sync.RWMutex
+
+	// cooldownCh delivers cooldown deadlines to the handler's select loop,
+	// and to an in-progress enforceCooldown's own select, so EnterCooldown
+	// can both wake the loop and extend a cooldown that's already being
+	// actively slept through, without waiting for either to poll for it.
+	// It is buffered so that EnterCooldown can push to it without blocking.
+	cooldownCh chan time.Time
+
+	// cooldownUntil is the absolute time the cooldown currently in effect
+	// ends - whether it's still queued on cooldownCh or already being
+	// enforced - so concurrent EnterCooldown calls can coalesce against
+	// whichever is true instead of only against the queued value.
+	cooldownUntil     time.Time
+	cooldownUntilLock sync.Mutex
 
 	// running is used to help keep track if the handler is active or not.
 	running     bool
@@ -52,20 +101,148 @@ type Handler struct {
 	doneCh chan struct{}
 }
 
-// NewHandler returns a new handler for a policy.
-func NewHandler(ID PolicyID, log hclog.Logger, pm *manager.PluginManager, ps Source) *Handler {
+// defaultPolicyReadTimeout is used when NewHandler is called with a
+// policyReadTimeout <= 0.
+const defaultPolicyReadTimeout = 3 * time.Minute
+
+// defaultElectionRetryInterval is used when NewHandler is called with an
+// electionRetryInterval <= 0.
+const defaultElectionRetryInterval = 30 * time.Second
+
+// NewHandler returns a new handler for a policy. policyReadTimeout bounds
+// how long the handler waits for the policy source to deliver the policy
+// before giving up; a value <= 0 falls back to defaultPolicyReadTimeout.
+// electionRetryInterval bounds how long the handler waits before retrying
+// after a failed attempt to acquire leadership; a value <= 0 falls back to
+// defaultElectionRetryInterval. ntfr is optional; a nil notifier means the
+// handler simply won't publish events. le is optional; a nil LeaderElector
+// falls back to a no-op elector that is always the leader, matching
+// single-node deployments.
+func NewHandler(ID PolicyID, log hclog.Logger, pm *manager.PluginManager, ps Source, policyReadTimeout, electionRetryInterval time.Duration, ntfr *notifier.Notifier, le LeaderElector) *Handler {
+	if policyReadTimeout <= 0 {
+		policyReadTimeout = defaultPolicyReadTimeout
+	}
+	if electionRetryInterval <= 0 {
+		electionRetryInterval = defaultElectionRetryInterval
+	}
+	if le == nil {
+		le = NewNoopLeaderElector()
+	}
+
 	return &Handler{
-		policyID:      ID,
-		log:           log.Named("policy_handler").With("policy_id", ID),
-		pluginManager: pm,
-		policySource:  ps,
-		ch:            make(chan Policy),
-		errCh:         make(chan error),
-		doneCh:        make(chan struct{}),
-		cooldownCh:    make(chan time.Duration),
+		policyID:              ID,
+		log:                   log.Named("policy_handler").With("policy_id", ID),
+		pluginManager:         pm,
+		policySource:          ps,
+		policyReadTimeout:     policyReadTimeout,
+		electionRetryInterval: electionRetryInterval,
+		notifier:              ntfr,
+		leaderElector:         le,
+		ch:                    make(chan Policy),
+		errCh:                 make(chan error),
+		doneCh:                make(chan struct{}),
+		cooldownCh:        make(chan time.Time, 1),
 	}
 }
 
+// EnterCooldown instructs the handler to enter a cooldown period of at
+// least d, without waiting for the handler's next evaluation tick to
+// observe it. This lets sibling subsystems - a notification handler
+// reacting to a Nomad deployment event, a webhook receiver, an HA leader
+// handoff - immediately quiesce a policy.
+//
+// EnterCooldown does not block. If a cooldown is already in effect - either
+// still queued, or already being actively enforced by enforceCooldown - the
+// two are coalesced: the one in effect is extended if d would end later,
+// and left alone (d is dropped) if it would end sooner or at the same time.
+func (h *Handler) EnterCooldown(d time.Duration) error {
+	h.runningLock.RLock()
+	running := h.running
+	h.runningLock.RUnlock()
+
+	if !running {
+		return fmt.Errorf("handler for policy %s is not running", h.policyID)
+	}
+
+	until, extended := h.armCooldown(d)
+	if !extended {
+		return nil
+	}
+
+	select {
+	case h.cooldownCh <- until:
+	default:
+		// A shorter cooldown is already queued; drain it and replace it
+		// with the longer one. If enforceCooldown is instead already
+		// actively reading this channel, the send above simply hands off
+		// directly to it instead of hitting this branch.
+		select {
+		case <-h.cooldownCh:
+		default:
+		}
+		h.cooldownCh <- until
+	}
+
+	return nil
+}
+
+// armCooldown records d (measured from now) as the handler's cooldown
+// deadline, coalescing it with whatever is already in effect, and reports
+// whether that moved the deadline later. It's shared by EnterCooldown and
+// the handler's own out-of-band cooldown detection in Run, so both agree
+// on a single deadline that enforceCooldown can then watch cooldownCh to
+// extend further.
+func (h *Handler) armCooldown(d time.Duration) (until time.Time, extended bool) {
+	candidate := time.Now().Add(d)
+
+	h.cooldownUntilLock.Lock()
+	defer h.cooldownUntilLock.Unlock()
+
+	if !h.cooldownUntil.IsZero() && !candidate.After(h.cooldownUntil) {
+		return h.cooldownUntil, false
+	}
+	h.cooldownUntil = candidate
+	return candidate, true
+}
+
+// publish publishes an Event for the handler's policy to the configured
+// notifier. It is a no-op if no notifier was supplied to NewHandler.
+func (h *Handler) publish(topic string, evt Event) {
+	if h.notifier == nil {
+		return
+	}
+	evt.PolicyID = h.policyID
+	if evt.Time.IsZero() {
+		evt.Time = time.Now().UTC()
+	}
+	h.notifier.Publish(topic, evt)
+}
+
+// armSchedule (re-)arms h.sched and stores the resulting channel in
+// h.schedCh for the select loop in Run to read from. It must be called
+// again after every fire (and whenever h.sched is rebuilt) to arm the next
+// one; h.schedCh must never be armed by calling h.sched.Next() directly
+// inside a select case, since select re-evaluates every case's channel
+// expression on every pass through the loop, not just when that case
+// fires.
+func (h *Handler) armSchedule() {
+	h.schedCh = h.sched.Next()
+}
+
+// setLeader records the handler's current leadership state.
+func (h *Handler) setLeader(leader bool) {
+	h.isLeaderLock.Lock()
+	h.isLeader = leader
+	h.isLeaderLock.Unlock()
+}
+
+// isLeading reports the handler's current leadership state.
+func (h *Handler) isLeading() bool {
+	h.isLeaderLock.RLock()
+	defer h.isLeaderLock.RUnlock()
+	return h.isLeader
+}
+
 // Run starts the handler and periodically sends the policy for evaluation.
 //
 // This function blocks until the context provided is canceled or the handler
@@ -83,18 +260,27 @@ func (h *Handler) Run(ctx context.Context, evalCh chan<- *Evaluation) {
 	// Store a local copy of the policy so we can compare it for changes.
 	var currentPolicy *Policy
 
-	// Start with a long ticker until we receive the right interval.
-	// TODO(luiz): make this a config param
-	policyReadTimeout := 3 * time.Minute
-	h.ticker = time.NewTicker(policyReadTimeout)
+	// Start with a long interval until we receive the policy and know its
+	// real schedule. It isn't armed yet: only the leader needs to detect a
+	// policy read timeout, so arming waits for leadership below.
+	h.sched = newIntervalScheduler(h.policyReadTimeout)
 
 	// Create separate context so we can stop the monitoring Go routine if
 	// doneCh is closed, but ctx is still valid.
 	monitorCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	// Start monitoring the policy for changes.
-	go h.policySource.MonitorPolicy(monitorCtx, h.policyID, h.ch, h.errCh)
+	// Start monitoring the policy for changes. This runs for as long as the
+	// handler does, regardless of leadership, so policy state stays warm
+	// while in standby.
+	go h.policySource.Watch(monitorCtx, h.policyID, h.ch, h.errCh)
+
+	// leaderCh/standbyCh are driven by runElection in the background so the
+	// loop below never blocks waiting on leadership; this keeps the handler
+	// free to keep processing policy updates while in standby.
+	leaderCh := make(chan struct{})
+	standbyCh := make(chan struct{})
+	go h.runElection(monitorCtx, leaderCh, standbyCh)
 
 	for {
 		select {
@@ -120,8 +306,26 @@ func (h *Handler) Run(ctx context.Context, evalCh chan<- *Evaluation) {
 		case p := <-h.ch:
 			h.updateHandler(currentPolicy, &p)
 			currentPolicy = &p
-		case <-h.ticker.C:
-
+		case <-leaderCh:
+			h.log.Debug("acquired leadership")
+			h.setLeader(true)
+
+			// Arm (or re-arm) the schedule now that this instance is
+			// actually going to dispatch evaluations. While in standby the
+			// schedule is left unarmed, so a standby instance that never
+			// becomes leader doesn't spuriously report a policy read
+			// timeout for a policy it was never meant to evaluate.
+			h.armSchedule()
+		case <-standbyCh:
+			h.log.Debug("lost leadership, returning to standby")
+			h.setLeader(false)
+
+			// Stop the schedule and leave it unarmed; schedCh being nil
+			// means the case below simply never fires until armSchedule is
+			// called again on reacquiring leadership.
+			h.sched.Stop()
+			h.schedCh = nil
+		case <-h.schedCh:
 			// Timestamp the invocation of this evaluation run. This can be
 			// used when checking cooldown or emitting metrics to ensure some
 			// consistency.
@@ -136,6 +340,8 @@ func (h *Handler) Run(ctx context.Context, evalCh chan<- *Evaluation) {
 			// If the evaluation is nil there is nothing to be done this time
 			// around.
 			if eval == nil {
+				h.armSchedule()
+				h.publish(TopicPolicyEvaluationSkipped, Event{})
 				continue
 			}
 
@@ -158,11 +364,33 @@ func (h *Handler) Run(ctx context.Context, evalCh chan<- *Evaluation) {
 
 						cdPeriod := h.calculateRemainingCooldown(currentPolicy.Cooldown, curTime, int64(lastTS))
 
+						// Arm (or coalesce into) the handler's shared
+						// cooldown deadline so a concurrent EnterCooldown
+						// extends this same sleep instead of stacking a
+						// second one behind it.
+						until, _ := h.armCooldown(cdPeriod)
+
+						h.publish(TopicPolicyCooldownEnter, Event{Cooldown: time.Until(until)})
+
+						start := time.Now()
+
 						// Enforce the cooldown which will block until complete.
-						if !h.enforceCooldown(ctx, cdPeriod) {
+						if !h.enforceCooldown(ctx, until) {
 							return
 						}
 
+						elapsed := time.Since(start)
+
+						// Only now, after the cooldown sleep has actually
+						// elapsed, do we adjust and arm the next fire - the
+						// schedule was never armed for this cycle, so there
+						// is no already-running timer whose countdown would
+						// otherwise double-count the time just spent in
+						// cooldown.
+						h.adjustScheduleForCooldown(elapsed)
+						h.armSchedule()
+						h.publish(TopicPolicyCooldownExit, Event{Cooldown: elapsed})
+
 						// After the cooldown, the evaluation data is
 						// potentially stale. Therefore continue and allow a
 						// new tick to occur.
@@ -171,16 +399,42 @@ func (h *Handler) Run(ctx context.Context, evalCh chan<- *Evaluation) {
 				}
 			}
 
+			h.armSchedule()
+
 			// If we got this far, the evaluation can be sent to the channel
 			// for processing.
 			evalCh <- eval
 
-		case ts := <-h.cooldownCh:
+		case until := <-h.cooldownCh:
+
+			if !h.isLeading() {
+				// Not dispatching evaluations while in standby, so there is
+				// nothing to place into cooldown.
+				h.cooldownUntilLock.Lock()
+				h.cooldownUntil = time.Time{}
+				h.cooldownUntilLock.Unlock()
+				continue
+			}
+
+			h.publish(TopicPolicyCooldownEnter, Event{Cooldown: time.Until(until)})
+
+			start := time.Now()
 
-			// Enforce the cooldown which will block until complete.
-			if !h.enforceCooldown(ctx, ts) {
+			// Enforce the cooldown which will block until complete. It
+			// keeps watching cooldownCh itself so a longer cooldown that
+			// coalesces in while this is already sleeping extends it,
+			// rather than queuing up behind it.
+			if !h.enforceCooldown(ctx, until) {
 				return
 			}
+
+			// Unlike the schedCh case above, the schedule here was already
+			// armed before this cooldown began (it's driven independently by
+			// cooldownCh, not by a tick of the schedule itself), so its
+			// timer has already been counting down in real time for the
+			// whole cooldown sleep. Adjusting it again here would subtract
+			// the elapsed cooldown twice.
+			h.publish(TopicPolicyCooldownExit, Event{Cooldown: time.Since(start)})
 		}
 	}
 }
@@ -192,13 +446,54 @@ func (h *Handler) Stop() {
 
 	if h.running {
 		h.log.Trace("stopping handler")
-		h.ticker.Stop()
+		h.sched.Stop()
+		h.leaderElector.StepDown()
 		close(h.doneCh)
 	}
 
 	h.running = false
 }
 
+// runElection repeatedly acquires leadership via h.leaderElector and
+// notifies the Run loop of leadership transitions on leaderCh/standbyCh, so
+// Run never blocks waiting for Acquire to return. It stops when ctx is
+// canceled.
+func (h *Handler) runElection(ctx context.Context, leaderCh, standbyCh chan<- struct{}) {
+	for {
+		lost, err := h.leaderElector.Acquire(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			h.log.Error("failed to acquire leadership, retrying", "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(h.electionRetryInterval):
+			}
+			continue
+		}
+
+		select {
+		case leaderCh <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+
+		select {
+		case <-lost:
+		case <-ctx.Done():
+			return
+		}
+
+		select {
+		case standbyCh <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 // generateEvaluation returns an evaluation if the policy needs to be evaluated.
 // Returning an error will stop the handler.
 func (h *Handler) generateEvaluation(policy *Policy) (*Evaluation, error) {
@@ -241,6 +536,7 @@ func (h *Handler) generateEvaluation(policy *Policy) (*Evaluation, error) {
 	// monitor the policy anymore.
 	if status == nil {
 		h.log.Trace("target doesn't exist anymore", "target", policy.Target.Config)
+		h.publish(TopicPolicyTargetGone, Event{})
 		h.Stop()
 		return nil, nil
 	}
@@ -264,45 +560,105 @@ func (h *Handler) generateEvaluation(policy *Policy) (*Evaluation, error) {
 func (h *Handler) updateHandler(current, next *Policy) {
 	if current == nil {
 		h.log.Trace("received policy")
+		h.publish(TopicPolicyReceived, Event{})
 	} else {
+		diff := cmp.Diff(current, next)
 		h.log.Trace("received policy change")
-		h.log.Trace(cmp.Diff(current, next))
+		h.log.Trace(diff)
+		h.publish(TopicPolicyChanged, Event{Diff: diff})
+	}
+
+	// Rebuild the scheduler if it's the first time we receive the policy or
+	// if the policy's schedule or evaluation interval has changed.
+	if current == nil || current.EvaluationInterval != next.EvaluationInterval || !schedulesEqual(current.Schedule, next.Schedule) {
+		sched, err := newScheduler(next.Schedule, next.EvaluationInterval)
+		if err != nil {
+			h.log.Error("failed to build schedule, keeping previous schedule", "error", err)
+			return
+		}
+
+		// Fire the first evaluation immediately rather than waiting a full
+		// interval/schedule period, if requested.
+		if current == nil && next.EvaluateOnStart {
+			if firer, ok := sched.(immediateFirer); ok {
+				firer.FireImmediately()
+			}
+		}
+
+		if h.sched != nil {
+			h.sched.Stop()
+		}
+		h.sched = sched
+
+		// Only arm the new schedule while leading; a standby instance
+		// leaves it unarmed until it takes over, at which point the
+		// leaderCh case arms whatever scheduler is current at that time.
+		if h.isLeading() {
+			h.armSchedule()
+		}
 	}
+}
 
-	// Update ticker if it's the first time we receive the policy or if the
-	// policy's evaluation interval has changed.
-	if current == nil || current.EvaluationInterval != next.EvaluationInterval {
-		h.ticker.Stop()
-		h.ticker = time.NewTicker(next.EvaluationInterval)
+// adjustScheduleForCooldown arms a one-shot pull-forward of the handler's
+// next scheduled fire by elapsed when the scheduler supports it, so a tick
+// that fell due while the handler was in cooldown doesn't end up sleeping a
+// full interval past when it should have fired. It must only be called for
+// a schedule that hasn't been armed for its next fire yet; see the
+// cooldownOverride comment on intervalScheduler for why.
+func (h *Handler) adjustScheduleForCooldown(elapsed time.Duration) {
+	if ca, ok := h.sched.(cooldownAware); ok {
+		ca.AdjustForCooldown(elapsed)
 	}
 }
 
-// enforceCooldown blocks until the cooldown period has been reached, or the
-// handler has been instructed to exit. The boolean return details whether or
-// not the cooldown period passed without being interrupted.
-func (h *Handler) enforceCooldown(ctx context.Context, t time.Duration) bool {
+// enforceCooldown blocks until until is reached, or the handler has been
+// instructed to exit. While sleeping it keeps watching cooldownCh, so a
+// longer cooldown that coalesces in via EnterCooldown while this call is
+// already in progress actually extends the sleep, rather than merely being
+// queued up to run sequentially once this one completes. The boolean
+// return details whether or not the cooldown period passed without being
+// interrupted.
+func (h *Handler) enforceCooldown(ctx context.Context, until time.Time) bool {
 
 	// Log that cooldown is being enforced. This is very useful as cooldown
 	// blocks the ticker making this the only indication of cooldown to
 	// operators.
-	h.log.Debug("scaling policy has been placed into cooldown", "cooldown", t)
+	h.log.Debug("scaling policy has been placed into cooldown", "cooldown", time.Until(until))
 
 	// Using a timer directly is mentioned to be more efficient than
 	// time.After() as long as we ensure to call Stop(). So setup a timer for
-	// use and defer the stop.
-	timer := time.NewTimer(t)
-	defer timer.Stop()
+	// use and defer the leak-safe stop.
+	timer, stop := NewSafeTimer(time.Until(until))
+	defer func() { stop() }()
 
 	// Cooldown should not mean we miss other handler control signals. So wait
 	// on all the channels desired here.
-	select {
-	case <-timer.C:
-		return true
-	case <-ctx.Done():
-		return false
-	case <-h.doneCh:
-		return false
-
+	for {
+		select {
+		case <-timer.C:
+			// Only clear the deadline if nothing extended it past until
+			// after we started waiting on timer.C for this round; a
+			// concurrent EnterCooldown that coalesced in since would have
+			// updated cooldownUntil but couldn't reach us on cooldownCh in
+			// time, and must not be silently dropped.
+			h.cooldownUntilLock.Lock()
+			if !h.cooldownUntil.After(until) {
+				h.cooldownUntil = time.Time{}
+			}
+			h.cooldownUntilLock.Unlock()
+			return true
+		case until = <-h.cooldownCh:
+			// EnterCooldown/armCooldown only ever push a deadline that
+			// coalesces to later than whatever's already in effect, so
+			// this is always an extension.
+			h.log.Debug("cooldown extended", "cooldown", time.Until(until))
+			stop()
+			timer, stop = NewSafeTimer(time.Until(until))
+		case <-ctx.Done():
+			return false
+		case <-h.doneCh:
+			return false
+		}
 	}
 }
 
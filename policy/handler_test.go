@@ -0,0 +1,163 @@
+package policy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+func newTestHandler() *Handler {
+	return &Handler{
+		policyID:   "test-policy",
+		log:        hclog.NewNullLogger(),
+		running:    true,
+		doneCh:     make(chan struct{}),
+		cooldownCh: make(chan time.Time, 1),
+	}
+}
+
+// withinTolerance reports whether got is within d of want, to absorb the
+// handful of milliseconds between computing an expected deadline and the
+// EnterCooldown call that derives its own from time.Now().
+func withinTolerance(t *testing.T, got, want time.Time, d time.Duration) bool {
+	t.Helper()
+	diff := got.Sub(want)
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= d
+}
+
+func TestHandler_EnterCooldown_QueuesWhenIdle(t *testing.T) {
+	h := newTestHandler()
+
+	want := time.Now().Add(time.Minute)
+	if err := h.EnterCooldown(time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case until := <-h.cooldownCh:
+		if !withinTolerance(t, until, want, time.Second) {
+			t.Fatalf("expected queued deadline around %s, got %s", want, until)
+		}
+	default:
+		t.Fatal("expected a cooldown to be queued")
+	}
+}
+
+func TestHandler_EnterCooldown_ExtendsAShorterPending(t *testing.T) {
+	h := newTestHandler()
+
+	if err := h.EnterCooldown(30 * time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := time.Now().Add(time.Minute)
+	if err := h.EnterCooldown(time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case until := <-h.cooldownCh:
+		if !withinTolerance(t, until, want, time.Second) {
+			t.Fatalf("expected pending cooldown to extend to around %s, got %s", want, until)
+		}
+	default:
+		t.Fatal("expected a cooldown to be queued")
+	}
+}
+
+func TestHandler_EnterCooldown_KeepsALongerPending(t *testing.T) {
+	h := newTestHandler()
+
+	want := time.Now().Add(time.Minute)
+	if err := h.EnterCooldown(time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := h.EnterCooldown(30 * time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case until := <-h.cooldownCh:
+		if !withinTolerance(t, until, want, time.Second) {
+			t.Fatalf("expected pending cooldown to stay around %s, got %s", want, until)
+		}
+	default:
+		t.Fatal("expected a cooldown to be queued")
+	}
+}
+
+func TestHandler_EnterCooldown_ErrorsWhenNotRunning(t *testing.T) {
+	h := newTestHandler()
+	h.running = false
+
+	if err := h.EnterCooldown(time.Minute); err == nil {
+		t.Fatal("expected an error for a handler that isn't running")
+	}
+}
+
+// TestHandler_EnterCooldown_CoalescesAgainstAnActiveCooldown covers the
+// case that comparing only against the value queued on cooldownCh would
+// miss: a cooldown that is already being actively enforced (not merely sitting in cooldownCh) must still be
+// extended by a longer EnterCooldown, and must still absorb a shorter or
+// equal one instead of letting it queue up to run afterward.
+func TestHandler_EnterCooldown_CoalescesAgainstAnActiveCooldown(t *testing.T) {
+	h := newTestHandler()
+
+	// Simulate enforceCooldown already actively sleeping through a 1m
+	// cooldown: cooldownUntil is set, but nothing is queued on cooldownCh.
+	active := time.Now().Add(time.Minute)
+	h.cooldownUntil = active
+
+	if err := h.EnterCooldown(30 * time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-h.cooldownCh:
+		t.Fatal("a shorter cooldown must not queue up behind an active one")
+	default:
+	}
+	if h.cooldownUntil != active {
+		t.Fatalf("expected active deadline to be left untouched, got %s", h.cooldownUntil)
+	}
+
+	want := time.Now().Add(2 * time.Minute)
+	if err := h.EnterCooldown(2 * time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case until := <-h.cooldownCh:
+		if !withinTolerance(t, until, want, time.Second) {
+			t.Fatalf("expected the active cooldown to extend to around %s, got %s", want, until)
+		}
+	default:
+		t.Fatal("expected the extension to be pushed so enforceCooldown can pick it up")
+	}
+}
+
+// TestHandler_EnforceCooldown_ExtendsWhileSleeping verifies enforceCooldown
+// itself keeps watching cooldownCh and actually extends its sleep when a
+// longer cooldown coalesces in mid-flight, rather than returning on the
+// original deadline and leaving the extension to run sequentially after.
+func TestHandler_EnforceCooldown_ExtendsWhileSleeping(t *testing.T) {
+	h := newTestHandler()
+
+	start := time.Now()
+	until := start.Add(100 * time.Millisecond)
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		h.cooldownCh <- start.Add(250 * time.Millisecond)
+	}()
+
+	if !h.enforceCooldown(context.Background(), until) {
+		t.Fatal("expected enforceCooldown to complete normally")
+	}
+
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Fatalf("expected the extension to push completion past 250ms, took %s", elapsed)
+	}
+}
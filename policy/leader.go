@@ -0,0 +1,41 @@
+package policy
+
+import "context"
+
+// LeaderElector decides which autoscaler instance, among potentially many
+// pointed at the same Nomad cluster, is allowed to dispatch evaluations for
+// a policy. Handler.Run stays in a standby state, still receiving policy
+// updates but not dispatching evaluations, until leadership is acquired, so
+// that hot-standby autoscalers never perform duplicate scaling actions.
+type LeaderElector interface {
+	// Acquire blocks until leadership is acquired or ctx is canceled. On
+	// success it returns a channel that is closed when leadership is later
+	// lost (e.g. a session is invalidated, or StepDown is called), so the
+	// caller can return to standby and call Acquire again.
+	Acquire(ctx context.Context) (<-chan struct{}, error)
+
+	// StepDown voluntarily relinquishes leadership, if currently held. It
+	// is safe to call even if leadership isn't held.
+	StepDown()
+}
+
+// noopLeaderElector is the default LeaderElector for single-node
+// deployments: the caller is always considered the leader.
+type noopLeaderElector struct{}
+
+// NewNoopLeaderElector returns a LeaderElector that grants leadership
+// immediately and only loses it when ctx is canceled.
+func NewNoopLeaderElector() LeaderElector {
+	return &noopLeaderElector{}
+}
+
+func (e *noopLeaderElector) Acquire(ctx context.Context) (<-chan struct{}, error) {
+	lost := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		close(lost)
+	}()
+	return lost, nil
+}
+
+func (e *noopLeaderElector) StepDown() {}
@@ -0,0 +1,105 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+)
+
+// consulLeaderElector implements LeaderElector using a Consul session-backed
+// lock, so that exactly one of several autoscaler instances pointed at the
+// same Consul cluster holds leadership at a time.
+type consulLeaderElector struct {
+	client *consulapi.Client
+	key    string
+
+	// lockRetryInterval is how long to wait between attempts to acquire the
+	// lock while it's held by another instance.
+	lockRetryInterval time.Duration
+
+	// leaderCheckInterval is how often held leadership is re-verified
+	// against Consul, in addition to relying on the lock's own lost
+	// channel.
+	leaderCheckInterval time.Duration
+
+	lockLock sync.Mutex
+	lock     *consulapi.Lock
+}
+
+// NewConsulLeaderElector returns a LeaderElector backed by a Consul lock
+// held at key.
+func NewConsulLeaderElector(client *consulapi.Client, key string, lockRetryInterval, leaderCheckInterval time.Duration) LeaderElector {
+	return &consulLeaderElector{
+		client:              client,
+		key:                 key,
+		lockRetryInterval:   lockRetryInterval,
+		leaderCheckInterval: leaderCheckInterval,
+	}
+}
+
+func (e *consulLeaderElector) Acquire(ctx context.Context) (<-chan struct{}, error) {
+	lock, err := e.client.LockKey(e.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consul lock: %v", err)
+	}
+
+	var lockLostCh <-chan struct{}
+	for lockLostCh == nil {
+		lockLostCh, err = lock.Lock(ctx.Done())
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire consul lock: %v", err)
+		}
+		if lockLostCh != nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(e.lockRetryInterval):
+		}
+	}
+
+	e.lockLock.Lock()
+	e.lock = lock
+	e.lockLock.Unlock()
+
+	lost := make(chan struct{})
+	go e.monitor(ctx, lockLostCh, lost)
+	return lost, nil
+}
+
+// monitor closes lost as soon as either the Consul lock reports it was
+// lost, or a periodic health check against the lock key fails.
+func (e *consulLeaderElector) monitor(ctx context.Context, lockLostCh <-chan struct{}, lost chan<- struct{}) {
+	defer close(lost)
+
+	ticker := time.NewTicker(e.leaderCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-lockLostCh:
+			return
+		case <-ticker.C:
+			if _, _, err := e.client.KV().Get(e.key, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (e *consulLeaderElector) StepDown() {
+	e.lockLock.Lock()
+	defer e.lockLock.Unlock()
+
+	if e.lock != nil {
+		_ = e.lock.Unlock()
+		e.lock = nil
+	}
+}
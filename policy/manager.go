@@ -0,0 +1,57 @@
+package policy
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+// Manager tracks the running handlers for every policy known to the
+// autoscaler and exposes operations that act on a handler by PolicyID
+// without the caller needing a reference to it directly.
+type Manager struct {
+	log hclog.Logger
+
+	lock     sync.RWMutex
+	handlers map[PolicyID]*Handler
+}
+
+// NewManager returns a new, empty Manager.
+func NewManager(log hclog.Logger) *Manager {
+	return &Manager{
+		log:      log.Named("policy_manager"),
+		handlers: make(map[PolicyID]*Handler),
+	}
+}
+
+// SetHandler registers h as the handler responsible for policyID, replacing
+// any previous handler registered for that ID.
+func (m *Manager) SetHandler(policyID PolicyID, h *Handler) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.handlers[policyID] = h
+}
+
+// RemoveHandler forgets the handler registered for policyID.
+func (m *Manager) RemoveHandler(policyID PolicyID) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.handlers, policyID)
+}
+
+// PushCooldown pushes a cooldown of duration d into the running handler for
+// policyID, without waiting for the handler's next evaluation tick to
+// observe it.
+func (m *Manager) PushCooldown(policyID PolicyID, d time.Duration) error {
+	m.lock.RLock()
+	h, ok := m.handlers[policyID]
+	m.lock.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("no handler registered for policy %s", policyID)
+	}
+
+	return h.EnterCooldown(d)
+}
@@ -0,0 +1,110 @@
+// Package notifier implements a small topic/handler pub-sub registry used to
+// decouple the policy handler loop from anything that wants to react to
+// policy lifecycle and evaluation events, such as audit logging, webhook
+// dispatchers, or a future metrics exporter.
+package notifier
+
+import (
+	"sync"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+// Handler receives values published to a topic it has subscribed to.
+type Handler interface {
+	// Notify is called with each value published to a subscribed topic.
+	Notify(topic string, value interface{})
+
+	// IsStateful reports whether this handler must observe events on a
+	// topic serially. Stateful handlers for a given topic are run one at a
+	// time, in publish order; stateless handlers are fanned out
+	// concurrently.
+	IsStateful() bool
+}
+
+// Notifier is a topic/handler registry. Subscribers register interest in a
+// topic and are invoked whenever a value is published to it.
+type Notifier struct {
+	log hclog.Logger
+
+	lock     sync.RWMutex
+	handlers map[string][]Handler
+
+	// statefulLock guards statefulTopics, which holds one mutex per topic so
+	// stateful handlers stay serialized across concurrent Publish calls, not
+	// just within a single one.
+	statefulLock   sync.Mutex
+	statefulTopics map[string]*sync.Mutex
+}
+
+// New returns a new, empty Notifier.
+func New(log hclog.Logger) *Notifier {
+	return &Notifier{
+		log:            log.Named("notifier"),
+		handlers:       make(map[string][]Handler),
+		statefulTopics: make(map[string]*sync.Mutex),
+	}
+}
+
+// Subscribe registers h to receive values published on topic.
+func (n *Notifier) Subscribe(topic string, h Handler) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.handlers[topic] = append(n.handlers[topic], h)
+}
+
+// Publish sends value to every handler subscribed to topic. Stateful
+// handlers for a given topic are serialized against every other Publish
+// call for that same topic, not just the handlers within this one call, and
+// run, in subscription order, before Publish returns; stateless handlers
+// are dispatched concurrently and Publish does not wait for them to finish.
+func (n *Notifier) Publish(topic string, value interface{}) {
+	n.lock.RLock()
+	handlers := append([]Handler(nil), n.handlers[topic]...)
+	n.lock.RUnlock()
+
+	var statefulLock *sync.Mutex
+
+	for _, h := range handlers {
+		if h.IsStateful() {
+			if statefulLock == nil {
+				statefulLock = n.topicLock(topic)
+				statefulLock.Lock()
+				defer statefulLock.Unlock()
+			}
+			n.dispatch(topic, h, value)
+			continue
+		}
+		go n.dispatch(topic, h, value)
+	}
+}
+
+// topicLock returns the mutex used to serialize stateful handler dispatch
+// for topic across concurrent Publish calls, creating it on first use.
+func (n *Notifier) topicLock(topic string) *sync.Mutex {
+	n.statefulLock.Lock()
+	defer n.statefulLock.Unlock()
+
+	l, ok := n.statefulTopics[topic]
+	if !ok {
+		l = &sync.Mutex{}
+		n.statefulTopics[topic] = l
+	}
+	return l
+}
+
+// dispatch invokes h.Notify, recovering from and logging any panic so that
+// one buggy subscriber cannot take down the publisher or its other
+// subscribers.
+func (n *Notifier) dispatch(topic string, h Handler, value interface{}) {
+	defer n.handleCrash(topic)
+	h.Notify(topic, value)
+}
+
+// handleCrash recovers a panic from a handler dispatch, logging it instead
+// of letting it propagate.
+func (n *Notifier) handleCrash(topic string) {
+	if r := recover(); r != nil {
+		n.log.Error("recovered from panic in notifier handler", "topic", topic, "panic", r)
+	}
+}
@@ -0,0 +1,97 @@
+package policy
+
+import (
+	"context"
+	"time"
+
+	targetpkg "github.com/hashicorp/nomad-autoscaler/plugins/target"
+)
+
+// PolicyID is a unique identifier for a policy.
+type PolicyID string
+
+// Source describes the methods implemented by backends that store scaling
+// policies, such as Nomad job meta, a directory of files, Consul KV, or an
+// HTTP endpoint. Backends are free to support only a subset of writes (Put/
+// Delete) if they are read-only, returning an error for the rest.
+type Source interface {
+	// MonitorIDs watches for changes to the list of policy IDs known to the
+	// source, sending the up to date list on ch whenever it changes.
+	MonitorIDs(ctx context.Context, ch chan<- []PolicyID, errCh chan<- error)
+
+	// List returns every policy currently known to the backend.
+	List(ctx context.Context) ([]*Policy, error)
+
+	// Get returns the policy for id, or nil if the backend has no policy
+	// with that ID.
+	Get(ctx context.Context, id PolicyID) (*Policy, error)
+
+	// Put creates or updates a policy.
+	Put(ctx context.Context, p *Policy) error
+
+	// Delete removes a policy.
+	Delete(ctx context.Context, id PolicyID) error
+
+	// Watch streams updates to a single policy, sending the updated policy
+	// on resultCh whenever it is modified, until ctx is canceled. It
+	// replaces what used to be called MonitorPolicy.
+	Watch(ctx context.Context, id PolicyID, resultCh chan<- Policy, errCh chan<- error)
+
+	// WatchAll streams add/update/delete events for every policy known to
+	// the backend, until ctx is canceled, so a manager can spin handlers up
+	// and down without re-polling List.
+	WatchAll(ctx context.Context, ch chan<- PolicyListEvent, errCh chan<- error)
+}
+
+// PolicyEventType identifies the kind of change a PolicyListEvent describes.
+type PolicyEventType string
+
+const (
+	PolicyEventAdded   PolicyEventType = "added"
+	PolicyEventUpdated PolicyEventType = "updated"
+	PolicyEventDeleted PolicyEventType = "deleted"
+)
+
+// PolicyListEvent is streamed by Source.WatchAll whenever a policy is added,
+// updated, or deleted.
+type PolicyListEvent struct {
+	Type     PolicyEventType
+	PolicyID PolicyID
+
+	// Policy is the policy's new state. It is nil for PolicyEventDeleted.
+	Policy *Policy
+}
+
+// Target identifies the plugin and configuration used to interact with the
+// thing being scaled.
+type Target struct {
+	Name   string
+	Config map[string]string
+}
+
+// Policy is the internal representation of a scaling policy and holds all
+// the information required to successfully evaluate it.
+type Policy struct {
+	ID                 PolicyID
+	Enabled            bool
+	Cooldown           time.Duration
+	EvaluationInterval time.Duration
+
+	// Schedule, when set, overrides EvaluationInterval with a cron or
+	// calendar based evaluation schedule.
+	Schedule *Schedule
+
+	// EvaluateOnStart causes the handler to perform its first evaluation
+	// immediately instead of waiting for the first interval or schedule
+	// fire to elapse.
+	EvaluateOnStart bool
+
+	Target *Target
+}
+
+// Evaluation is the information passed to workers so they can evaluate a
+// policy and, if required, perform a scaling action.
+type Evaluation struct {
+	Policy       *Policy
+	TargetStatus *targetpkg.Status
+}
@@ -0,0 +1,254 @@
+package policy
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/gorhill/cronexpr"
+)
+
+// Schedule configures when a policy's handler evaluates it, as an
+// alternative to the simple EvaluationInterval duration. At most one of
+// Cron or Calendar should be set; when both are empty the handler falls
+// back to EvaluationInterval.
+type Schedule struct {
+	// Cron is a standard five-field cron expression (e.g. "0 */5 * * *"),
+	// evaluated in UTC.
+	Cron string
+
+	// Calendar describes a simpler, human friendly recurring window as an
+	// alternative to writing a cron expression by hand.
+	Calendar *CalendarSchedule
+}
+
+// CalendarSchedule describes a daily or weekly recurrence at a fixed UTC
+// time of day, e.g. "every day at 02:00 UTC" or "every Monday at 09:30 UTC".
+type CalendarSchedule struct {
+	// Weekday restricts the schedule to a single day of the week. A nil
+	// value means the schedule fires every day.
+	Weekday *time.Weekday
+
+	// HourUTC and MinuteUTC give the time of day, in UTC, the schedule
+	// fires at.
+	HourUTC   int
+	MinuteUTC int
+}
+
+// scheduler produces the times at which a handler should evaluate its
+// policy. It replaces the previous bare *time.Ticker so handlers can opt
+// into cron or calendar based schedules in addition to a fixed interval.
+type scheduler interface {
+	// Next arms the schedule and returns the channel on which the next
+	// fire will be delivered. It must be called again after every fire (or
+	// after Stop) to arm the following one.
+	Next() <-chan time.Time
+
+	// Stop releases any resources held by the scheduler. Safe to call
+	// multiple times.
+	Stop()
+}
+
+// cooldownAware is implemented by schedulers whose next fire time should be
+// pulled forward by however long the handler just spent in cooldown, so a
+// tick that was already due doesn't end up sleeping a full interval past
+// when it should have fired.
+type cooldownAware interface {
+	AdjustForCooldown(elapsed time.Duration)
+}
+
+// immediateFirer is implemented by schedulers that can be told to fire once
+// straight away before resuming their normal cadence, used to back
+// Policy.EvaluateOnStart.
+type immediateFirer interface {
+	FireImmediately()
+}
+
+// newScheduler builds the scheduler described by s, falling back to a plain
+// interval scheduler when s is nil or empty.
+func newScheduler(s *Schedule, interval time.Duration) (scheduler, error) {
+	switch {
+	case s == nil:
+		return newIntervalScheduler(interval), nil
+	case s.Cron != "":
+		return newCronScheduler(s.Cron)
+	case s.Calendar != nil:
+		return newCalendarScheduler(s.Calendar), nil
+	default:
+		return newIntervalScheduler(interval), nil
+	}
+}
+
+// schedulesEqual reports whether two schedule specs are equivalent, taking
+// the nil Schedule and the zero-value Schedule as the same thing.
+func schedulesEqual(a, b *Schedule) bool {
+	if a == nil {
+		a = &Schedule{}
+	}
+	if b == nil {
+		b = &Schedule{}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// intervalScheduler is the default scheduler, firing at a fixed interval.
+// It is also used as the placeholder scheduler before the first policy is
+// read.
+type intervalScheduler struct {
+	interval        time.Duration
+	fireImmediately bool
+	timer           *time.Timer
+	stop            func()
+
+	// cooldownOverride, when set, is consumed by exactly one Next() call in
+	// place of interval, then cleared. It lets AdjustForCooldown pull the
+	// very next fire forward without permanently shortening every fire
+	// after it.
+	cooldownOverride *time.Duration
+}
+
+func newIntervalScheduler(interval time.Duration) *intervalScheduler {
+	return &intervalScheduler{interval: interval}
+}
+
+func (s *intervalScheduler) Next() <-chan time.Time {
+	d := s.interval
+	switch {
+	case s.cooldownOverride != nil:
+		d = *s.cooldownOverride
+		s.cooldownOverride = nil
+	case s.fireImmediately:
+		d = 0
+		s.fireImmediately = false
+	}
+
+	if s.timer == nil {
+		s.timer, s.stop = NewSafeTimer(d)
+	} else {
+		s.timer.Reset(d)
+	}
+	return s.timer.C
+}
+
+func (s *intervalScheduler) Stop() {
+	if s.stop != nil {
+		s.stop()
+	}
+}
+
+func (s *intervalScheduler) FireImmediately() {
+	s.fireImmediately = true
+}
+
+// AdjustForCooldown arms a one-shot override so the very next Next() call
+// fires after interval-elapsed instead of a full interval, accounting for
+// time already spent in cooldown. It leaves interval itself untouched, so
+// every fire after that one goes back to the configured cadence. Callers
+// must only invoke this for a fire that hasn't been armed yet - the timer
+// for a fire armed before cooldown was entered already keeps counting down
+// in real time while the handler blocks in cooldown, so adjusting it too
+// would subtract the elapsed cooldown twice.
+func (s *intervalScheduler) AdjustForCooldown(elapsed time.Duration) {
+	remaining := s.interval - elapsed
+	if remaining < 0 {
+		remaining = 0
+	}
+	s.cooldownOverride = &remaining
+}
+
+// cronScheduler fires according to a standard cron expression, evaluated in
+// UTC.
+type cronScheduler struct {
+	expr            *cronexpr.Expression
+	fireImmediately bool
+	timer           *time.Timer
+	stop            func()
+}
+
+func newCronScheduler(expr string) (*cronScheduler, error) {
+	parsed, err := cronexpr.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron schedule %q: %v", expr, err)
+	}
+	return &cronScheduler{expr: parsed}, nil
+}
+
+func (s *cronScheduler) Next() <-chan time.Time {
+	var d time.Duration
+	if s.fireImmediately {
+		s.fireImmediately = false
+	} else {
+		d = time.Until(s.expr.Next(time.Now().UTC()))
+	}
+
+	if s.timer == nil {
+		s.timer, s.stop = NewSafeTimer(d)
+	} else {
+		s.timer.Reset(d)
+	}
+	return s.timer.C
+}
+
+func (s *cronScheduler) Stop() {
+	if s.stop != nil {
+		s.stop()
+	}
+}
+
+func (s *cronScheduler) FireImmediately() {
+	s.fireImmediately = true
+}
+
+// calendarScheduler fires daily, or weekly on a given weekday, at a fixed
+// UTC time of day.
+type calendarScheduler struct {
+	spec            *CalendarSchedule
+	fireImmediately bool
+	timer           *time.Timer
+	stop            func()
+}
+
+func newCalendarScheduler(spec *CalendarSchedule) *calendarScheduler {
+	return &calendarScheduler{spec: spec}
+}
+
+func (s *calendarScheduler) Next() <-chan time.Time {
+	var d time.Duration
+	if s.fireImmediately {
+		s.fireImmediately = false
+	} else {
+		d = time.Until(s.next(time.Now().UTC()))
+	}
+
+	if s.timer == nil {
+		s.timer, s.stop = NewSafeTimer(d)
+	} else {
+		s.timer.Reset(d)
+	}
+	return s.timer.C
+}
+
+func (s *calendarScheduler) FireImmediately() {
+	s.fireImmediately = true
+}
+
+// next returns the next UTC time strictly after from that matches the
+// schedule's time of day (and weekday, if restricted).
+func (s *calendarScheduler) next(from time.Time) time.Time {
+	next := time.Date(from.Year(), from.Month(), from.Day(), s.spec.HourUTC, s.spec.MinuteUTC, 0, 0, time.UTC)
+	if !next.After(from) {
+		next = next.AddDate(0, 0, 1)
+	}
+	if s.spec.Weekday != nil {
+		for next.Weekday() != *s.spec.Weekday {
+			next = next.AddDate(0, 0, 1)
+		}
+	}
+	return next
+}
+
+func (s *calendarScheduler) Stop() {
+	if s.stop != nil {
+		s.stop()
+	}
+}
@@ -0,0 +1,80 @@
+package policy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIntervalScheduler_FireImmediately(t *testing.T) {
+	s := newIntervalScheduler(time.Hour)
+	defer s.Stop()
+
+	s.FireImmediately()
+
+	select {
+	case <-s.Next():
+	case <-time.After(time.Second):
+		t.Fatal("expected FireImmediately to make the first Next() fire right away")
+	}
+}
+
+func TestIntervalScheduler_AdjustForCooldownIsOneShot(t *testing.T) {
+	s := newIntervalScheduler(200 * time.Millisecond)
+	defer s.Stop()
+
+	s.AdjustForCooldown(150 * time.Millisecond)
+
+	start := time.Now()
+	select {
+	case <-s.Next():
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not fire")
+	}
+	if elapsed := time.Since(start); elapsed >= 150*time.Millisecond {
+		t.Fatalf("expected the adjusted fire to take less than 150ms, took %s", elapsed)
+	}
+
+	// AdjustForCooldown must not have mutated the configured interval: the
+	// following fire should take the full interval again.
+	if s.interval != 200*time.Millisecond {
+		t.Fatalf("expected interval to be left untouched at 200ms, got %s", s.interval)
+	}
+
+	start = time.Now()
+	select {
+	case <-s.Next():
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not fire")
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("expected the following fire to take the full interval, took %s", elapsed)
+	}
+}
+
+func TestSchedulesEqual(t *testing.T) {
+	weekday := time.Monday
+
+	cases := []struct {
+		name string
+		a, b *Schedule
+		want bool
+	}{
+		{"both nil", nil, nil, true},
+		{"nil and zero value", nil, &Schedule{}, true},
+		{"different cron", &Schedule{Cron: "0 * * * *"}, &Schedule{Cron: "0 0 * * *"}, false},
+		{
+			"equivalent calendar",
+			&Schedule{Calendar: &CalendarSchedule{Weekday: &weekday, HourUTC: 2}},
+			&Schedule{Calendar: &CalendarSchedule{Weekday: &weekday, HourUTC: 2}},
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := schedulesEqual(c.a, c.b); got != c.want {
+				t.Fatalf("schedulesEqual(%+v, %+v) = %v, want %v", c.a, c.b, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-multierror"
+)
+
+// CompositeSource merges multiple Source backends into one, in priority
+// order: the first backend (index 0) that knows about a policy ID wins.
+// This is the prerequisite for gitops-style policy management, e.g. a file
+// source that overrides whatever Nomad job meta says for the same policy
+// ID, and lets handler behavior be tested without a live Nomad server by
+// swapping in a fake backend.
+type CompositeSource struct {
+	log hclog.Logger
+
+	// backends is ordered from highest to lowest priority.
+	backends []Source
+}
+
+// NewCompositeSource returns a Source that merges backends, highest
+// priority first.
+func NewCompositeSource(log hclog.Logger, backends ...Source) *CompositeSource {
+	return &CompositeSource{
+		log:      log.Named("composite_source"),
+		backends: backends,
+	}
+}
+
+// MonitorIDs merges the ID lists reported by every backend, de-duplicating
+// by PolicyID.
+func (s *CompositeSource) MonitorIDs(ctx context.Context, ch chan<- []PolicyID, errCh chan<- error) {
+	type update struct {
+		backend int
+		ids     []PolicyID
+	}
+
+	updates := make(chan update)
+	for i, b := range s.backends {
+		i, b := i, b
+		idCh := make(chan []PolicyID)
+		bErrCh := make(chan error)
+		go b.MonitorIDs(ctx, idCh, bErrCh)
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case ids := <-idCh:
+					select {
+					case updates <- update{backend: i, ids: ids}:
+					case <-ctx.Done():
+						return
+					}
+				case err := <-bErrCh:
+					select {
+					case errCh <- err:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	latest := make([][]PolicyID, len(s.backends))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case u := <-updates:
+			latest[u.backend] = u.ids
+
+			seen := make(map[PolicyID]bool)
+			var merged []PolicyID
+			for _, ids := range latest {
+				for _, id := range ids {
+					if !seen[id] {
+						seen[id] = true
+						merged = append(merged, id)
+					}
+				}
+			}
+
+			select {
+			case ch <- merged:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// List returns the union of every backend's policies. When the same
+// PolicyID is defined in more than one backend, the higher priority
+// backend's copy is kept and the conflict is logged.
+func (s *CompositeSource) List(ctx context.Context) ([]*Policy, error) {
+	owners := make(map[PolicyID]int)
+	merged := make(map[PolicyID]*Policy)
+
+	for i, b := range s.backends {
+		policies, err := b.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("backend %d: %v", i, err)
+		}
+		for _, p := range policies {
+			if owner, ok := owners[p.ID]; ok {
+				s.log.Warn("policy defined in multiple sources, keeping higher priority one",
+					"policy_id", p.ID, "kept_backend", owner, "ignored_backend", i)
+				continue
+			}
+			owners[p.ID] = i
+			merged[p.ID] = p
+		}
+	}
+
+	out := make([]*Policy, 0, len(merged))
+	for _, p := range merged {
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// Get returns the policy for id from the highest priority backend that has
+// it.
+func (s *CompositeSource) Get(ctx context.Context, id PolicyID) (*Policy, error) {
+	for _, b := range s.backends {
+		p, err := b.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if p != nil {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+// Put writes to the highest priority backend only; lower priority backends
+// are left untouched so they keep acting as a fallback.
+func (s *CompositeSource) Put(ctx context.Context, p *Policy) error {
+	if len(s.backends) == 0 {
+		return fmt.Errorf("composite source has no backends configured")
+	}
+	return s.backends[0].Put(ctx, p)
+}
+
+// Delete removes id from every backend that has it.
+func (s *CompositeSource) Delete(ctx context.Context, id PolicyID) error {
+	var merr *multierror.Error
+	for i, b := range s.backends {
+		if err := b.Delete(ctx, id); err != nil {
+			merr = multierror.Append(merr, fmt.Errorf("backend %d: %v", i, err))
+		}
+	}
+	return merr.ErrorOrNil()
+}
+
+// Watch streams updates to id, forwarding only the updates reported by
+// whichever backend currently has the highest priority claim on it. Updates
+// from a lower priority backend while a higher priority one owns the
+// policy are logged and dropped.
+//
+// Ownership is sticky for the lifetime of this call: once a backend's
+// update is forwarded, Watch never falls back to a lower priority backend's
+// updates, even if the owning backend later stops returning them (e.g. a
+// file override for id is removed). A higher priority backend that reports
+// an update still takes ownership. Policy is a plain value, with no way to distinguish "no
+// change" from "this backend no longer has it", so Watch has no signal on
+// which to fall back to a lower priority backend. A policy actually being
+// deleted, as opposed to one backend's copy of it going away, is something
+// WatchAll does observe (via PolicyEventDeleted) and callers that need to
+// detect an ownership handoff should use it instead of Watch for id.
+func (s *CompositeSource) Watch(ctx context.Context, id PolicyID, resultCh chan<- Policy, errCh chan<- error) {
+	type update struct {
+		backend int
+		policy  Policy
+	}
+
+	updates := make(chan update)
+	for i, b := range s.backends {
+		i, b := i, b
+		bCh := make(chan Policy)
+		bErrCh := make(chan error)
+		go b.Watch(ctx, id, bCh, bErrCh)
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case p := <-bCh:
+					select {
+					case updates <- update{backend: i, policy: p}:
+					case <-ctx.Done():
+						return
+					}
+				case err := <-bErrCh:
+					select {
+					case errCh <- err:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	owner := len(s.backends)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case u := <-updates:
+			if u.backend > owner {
+				s.log.Debug("ignoring policy update from lower priority source",
+					"policy_id", id, "backend", u.backend, "owning_backend", owner)
+				continue
+			}
+			owner = u.backend
+
+			select {
+			case resultCh <- u.policy:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// WatchAll streams add/update/delete events merged across every backend,
+// resolving the same precedence and conflict-logging rules as List/Watch.
+func (s *CompositeSource) WatchAll(ctx context.Context, ch chan<- PolicyListEvent, errCh chan<- error) {
+	type tagged struct {
+		backend int
+		evt     PolicyListEvent
+	}
+
+	events := make(chan tagged)
+	for i, b := range s.backends {
+		i, b := i, b
+		bCh := make(chan PolicyListEvent)
+		bErrCh := make(chan error)
+		go b.WatchAll(ctx, bCh, bErrCh)
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case e := <-bCh:
+					select {
+					case events <- tagged{backend: i, evt: e}:
+					case <-ctx.Done():
+						return
+					}
+				case err := <-bErrCh:
+					select {
+					case errCh <- err:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	owners := make(map[PolicyID]int)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-events:
+			owner, known := owners[t.evt.PolicyID]
+
+			if t.evt.Type == PolicyEventDeleted {
+				// Only honor a deletion from the backend that currently
+				// owns the policy; a lower priority backend deleting its
+				// own (shadowed) copy shouldn't surface as a deletion.
+				if known && t.backend != owner {
+					continue
+				}
+				delete(owners, t.evt.PolicyID)
+			} else {
+				if known && t.backend > owner {
+					s.log.Debug("ignoring policy event from lower priority source",
+						"policy_id", t.evt.PolicyID, "backend", t.backend, "owning_backend", owner)
+					continue
+				}
+				owners[t.evt.PolicyID] = t.backend
+			}
+
+			select {
+			case ch <- t.evt:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
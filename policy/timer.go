@@ -0,0 +1,31 @@
+package policy
+
+import "time"
+
+// NewSafeTimer returns a timer that fires after d, along with a stop
+// function that stops the timer and drains its channel if it already
+// fired. Callers should always use the returned stop function instead of
+// timer.Stop() directly, since a bare Stop() leaves a fired-but-unread
+// value on the channel that a later Reset() can then deliver stale.
+//
+// d is tolerated to be <= 0, in which case the timer fires immediately
+// rather than panicking like time.NewTimer would for negative durations in
+// some runtimes.
+func NewSafeTimer(d time.Duration) (*time.Timer, func()) {
+	if d < 0 {
+		d = 0
+	}
+
+	t := time.NewTimer(d)
+
+	stop := func() {
+		if !t.Stop() {
+			select {
+			case <-t.C:
+			default:
+			}
+		}
+	}
+
+	return t, stop
+}
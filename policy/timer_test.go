@@ -0,0 +1,46 @@
+package policy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewSafeTimer_FiresForNonPositiveDuration(t *testing.T) {
+	for _, d := range []time.Duration{0, -time.Second} {
+		timer, stop := NewSafeTimer(d)
+
+		select {
+		case <-timer.C:
+		case <-time.After(time.Second):
+			t.Fatalf("timer with duration %s did not fire", d)
+		}
+
+		stop()
+	}
+}
+
+func TestNewSafeTimer_StopDrainsAFiredTimer(t *testing.T) {
+	timer, stop := NewSafeTimer(0)
+
+	// Give the timer a chance to fire and leave its value sitting on the
+	// channel before we stop it.
+	time.Sleep(10 * time.Millisecond)
+	stop()
+
+	select {
+	case <-timer.C:
+		t.Fatal("expected stop to drain the fired timer's channel")
+	default:
+	}
+}
+
+func TestNewSafeTimer_StopIsSafeOnAnUnfiredTimer(t *testing.T) {
+	timer, stop := NewSafeTimer(time.Minute)
+	stop()
+
+	select {
+	case <-timer.C:
+		t.Fatal("timer should not have fired")
+	default:
+	}
+}